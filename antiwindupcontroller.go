@@ -11,8 +11,9 @@ import (
 // Feedback Systems: An Introduction to Scientists and Engineers, 2008
 // (http://www.cds.caltech.edu/~murray/amwiki)
 //
-// The ControlError, ControlErrorIntegrand, ControlErrorIntegral and ControlErrorDerivative are prevented
-// from reaching +/- inf by clamping them to [-MaxFloat32, MaxFloat32].
+// The ControlError, PreviousDerivativeError, ControlErrorIntegrand, ControlErrorIntegral and
+// ControlErrorDerivative are prevented from reaching +/- inf by clamping them to
+// [-MaxFloat32, MaxFloat32].
 type AntiWindupController struct {
 	// Config for the AntiWindupController.
 	Config AntiWindupControllerConfig
@@ -30,6 +31,18 @@ type AntiWindupControllerConfig struct {
 	DerivativeGain float32
 	// AntiWindUpGain is the anti-windup tracking gain.
 	AntiWindUpGain float32
+	// AntiWindupMode selects the anti-windup strategy. The zero value is BackCalculation.
+	AntiWindupMode AntiWindupMode
+	// ProportionalSetpointWeight (b) weights the reference signal's contribution to the P term.
+	// The zero value is treated as 1, reproducing unweighted behavior.
+	ProportionalSetpointWeight float32
+	// DerivativeSetpointWeight (c) weights the reference signal's contribution to the D term
+	// when DerivativeSource is DerivativeOnError. The zero value is treated as 1, reproducing
+	// unweighted, derivative-on-error behavior.
+	DerivativeSetpointWeight float32
+	// DerivativeSource selects whether the D term acts on the control error or on the
+	// measurement alone. The zero value is DerivativeOnError.
+	DerivativeSource DerivativeSource
 	// IntegralDischargeTimeConstant is the time constant to discharge the integral state of the PID controller (s)
 	IntegralDischargeTimeConstant float32
 	// LowPassTimeConstant is the D part low-pass filter time constant => cut-off frequency 1/LowPassTimeConstant.
@@ -38,12 +51,18 @@ type AntiWindupControllerConfig struct {
 	MaxOutput float32
 	// MinOutput is the min output from the PID.
 	MinOutput float32
+	// MaxOutputSlewRate is the maximum rate of change of ControlSignal, in units per second.
+	// Zero disables slew-rate limiting.
+	MaxOutputSlewRate float32
 }
 
 // AntiWindupControllerState holds mutable state for a AntiWindupController.
 type AntiWindupControllerState struct {
 	// ControlError is the difference between reference and current value.
 	ControlError float32
+	// PreviousDerivativeError is the D term's error from the previous Update call, used to
+	// low-pass filter the derivative.
+	PreviousDerivativeError float32
 	// ControlErrorIntegrand is the control error integrand, which includes the anti-windup correction.
 	ControlErrorIntegrand float32
 	// ControlErrorIntegral is the control error integrand integrated over time.
@@ -76,17 +95,78 @@ func (c *AntiWindupController) Reset() {
 // Update the controller state.
 func (c *AntiWindupController) Update(input AntiWindupControllerInput) {
 	e := input.ReferenceSignal - input.ActualSignal
-	controlErrorIntegral := c.State.ControlErrorIntegrand*seconds(input.SamplingInterval) + c.State.ControlErrorIntegral
-	controlErrorDerivative := ((1/seconds(c.Config.LowPassTimeConstant))*(e-c.State.ControlError) +
+	proportionalError := setpointWeight(c.Config.ProportionalSetpointWeight)*input.ReferenceSignal - input.ActualSignal
+	derivativeError := -input.ActualSignal
+	if c.Config.DerivativeSource != DerivativeOnMeasurement {
+		derivativeError += setpointWeight(c.Config.DerivativeSetpointWeight) * input.ReferenceSignal
+	}
+	controlErrorDerivative := ((1/seconds(c.Config.LowPassTimeConstant))*(derivativeError-c.State.PreviousDerivativeError) +
 		c.State.ControlErrorDerivative) / (seconds(input.SamplingInterval)/seconds(c.Config.LowPassTimeConstant) + 1)
-	c.State.UnsaturatedControlSignal = e*c.Config.ProportionalGain + c.Config.IntegralGain*controlErrorIntegral +
+
+	controlErrorIntegral := c.State.ControlErrorIntegrand*seconds(input.SamplingInterval) + c.State.ControlErrorIntegral
+	unsaturatedControlSignal := proportionalError*c.Config.ProportionalGain + c.Config.IntegralGain*controlErrorIntegral +
 		c.Config.DerivativeGain*controlErrorDerivative + input.FeedForwardSignal
-	c.State.ControlSignal = max(c.Config.MinOutput, min(c.Config.MaxOutput, c.State.UnsaturatedControlSignal))
-	c.State.ControlErrorIntegrand = e + c.Config.AntiWindUpGain*(c.State.ControlSignal-c.State.UnsaturatedControlSignal)
-	c.State.ControlErrorIntegrand = max(-MaxFloat32, min(MaxFloat32, c.State.ControlErrorIntegrand))
+
+	frozen := c.conditionallyFrozen(e, unsaturatedControlSignal)
+	if frozen {
+		// Hold the integrator at its previous value instead of accumulating further.
+		controlErrorIntegral = c.State.ControlErrorIntegral
+		unsaturatedControlSignal = proportionalError*c.Config.ProportionalGain + c.Config.IntegralGain*controlErrorIntegral +
+			c.Config.DerivativeGain*controlErrorDerivative + input.FeedForwardSignal
+	}
+	c.State.UnsaturatedControlSignal = unsaturatedControlSignal
+	saturatedControlSignal := max(c.Config.MinOutput, min(c.Config.MaxOutput, unsaturatedControlSignal))
+	c.State.ControlSignal = c.slewLimited(saturatedControlSignal, input.SamplingInterval)
+
+	if !frozen {
+		controlErrorIntegrand := e
+		if c.Config.AntiWindupMode == BackCalculation || c.Config.AntiWindupMode == Both {
+			controlErrorIntegrand += c.Config.AntiWindUpGain * (c.State.ControlSignal - c.State.UnsaturatedControlSignal)
+		}
+		c.State.ControlErrorIntegrand = max(-MaxFloat32, min(MaxFloat32, controlErrorIntegrand))
+	}
 	c.State.ControlErrorIntegral = max(-MaxFloat32, min(MaxFloat32, controlErrorIntegral))
 	c.State.ControlErrorDerivative = max(-MaxFloat32, min(MaxFloat32, controlErrorDerivative))
 	c.State.ControlError = max(-MaxFloat32, min(MaxFloat32, e))
+	c.State.PreviousDerivativeError = max(-MaxFloat32, min(MaxFloat32, derivativeError))
+}
+
+// conditionallyFrozen reports whether ConditionalIntegration or Both mode should hold the
+// integrator at its previous value for the current step: the output is saturated and the
+// sign of e would drive it further into saturation.
+func (c *AntiWindupController) conditionallyFrozen(e, unsaturatedControlSignal float32) bool {
+	if c.Config.AntiWindupMode != ConditionalIntegration && c.Config.AntiWindupMode != Both {
+		return false
+	}
+	return (unsaturatedControlSignal > c.Config.MaxOutput && e > 0) ||
+		(unsaturatedControlSignal < c.Config.MinOutput && e < 0)
+}
+
+// slewLimited clamps target to within MaxOutputSlewRate of the previously emitted
+// ControlSignal. A zero MaxOutputSlewRate disables limiting.
+func (c *AntiWindupController) slewLimited(target float32, dt time.Duration) float32 {
+	if c.Config.MaxOutputSlewRate == 0 {
+		return target
+	}
+	maxDelta := c.Config.MaxOutputSlewRate * seconds(dt)
+	return max(c.State.ControlSignal-maxDelta, min(c.State.ControlSignal+maxDelta, target))
+}
+
+// SetConfig applies newConfig as a bumpless update: when the new IntegralGain is nonzero,
+// ControlErrorIntegral is re-seeded so that, under the new gains, ProportionalGain*ControlError +
+// IntegralGain*ControlErrorIntegral + DerivativeGain*ControlErrorDerivative equals the last
+// ControlSignal, cancelling the P and D term jumps caused by the new gains. The re-seed takes the
+// P term from the unweighted ControlError and does not account for FeedForwardSignal, so with a
+// ProportionalSetpointWeight other than 1 or a nonzero feed-forward signal the next ControlSignal
+// still jumps by the difference. When the new IntegralGain is
+// zero, ControlErrorIntegral is left as-is, since it no longer contributes to ControlSignal.
+func (c *AntiWindupController) SetConfig(newConfig AntiWindupControllerConfig) {
+	c.Config = newConfig
+	if newConfig.IntegralGain != 0 {
+		c.State.ControlErrorIntegral = (c.State.ControlSignal -
+			newConfig.ProportionalGain*c.State.ControlError -
+			newConfig.DerivativeGain*c.State.ControlErrorDerivative) / newConfig.IntegralGain
+	}
 }
 
 // DischargeIntegral provides the ability to discharge the controller integral state
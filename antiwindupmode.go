@@ -0,0 +1,18 @@
+package tinypid
+
+// AntiWindupMode selects how AntiWindupController and TrackingController counteract
+// integrator windup while the control signal is saturated.
+type AntiWindupMode int
+
+const (
+	// BackCalculation feeds the saturation error back into the integrator through
+	// AntiWindUpGain. This is the default and matches prior behavior.
+	BackCalculation AntiWindupMode = iota
+	// ConditionalIntegration freezes the integrator for the current step whenever the
+	// output is saturated and the control error would drive it further into saturation,
+	// as in the PX4 "don't update the integrator if it would exceed the limit" pattern.
+	ConditionalIntegration
+	// Both freezes the integrator per ConditionalIntegration and, when not frozen,
+	// applies BackCalculation.
+	Both
+)
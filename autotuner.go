@@ -0,0 +1,299 @@
+package tinypid
+
+import (
+	"math"
+	"time"
+)
+
+// TuningRule selects the formula an AutoTuner uses to derive PID gains from
+// the identified ultimate gain Ku and ultimate period Tu.
+type TuningRule int
+
+const (
+	// ZieglerNichols is the classic Ziegler–Nichols closed-loop rule:
+	// Kp=0.6Ku, Ti=0.5Tu, Td=0.125Tu.
+	ZieglerNichols TuningRule = iota
+	// PessenIntegral favors faster integral action at the cost of more overshoot:
+	// Kp=0.7Ku, Ti=0.4Tu, Td=0.15Tu.
+	PessenIntegral
+	// SomeOvershoot targets a response with reduced overshoot relative to ZieglerNichols:
+	// Kp=0.33Ku, Ti=0.5Tu, Td=0.33Tu.
+	SomeOvershoot
+	// NoOvershoot targets a conservative, essentially non-overshooting response:
+	// Kp=0.2Ku, Ti=0.5Tu, Td=0.33Tu.
+	NoOvershoot
+)
+
+// AutoTuner identifies the ultimate gain and period of a process using relay
+// feedback and derives PID gains from them.
+//
+// The caller drives the identification experiment, typically from inside
+// the same control loop that will later run the tuned controller: on every
+// sampling interval it measures the process variable and calls Step, which
+// returns the relay output to apply until enough oscillation periods have
+// been observed to produce a stable estimate. This is the describing-function
+// relay auto-tuning method of Åström and Hägglund, Automatic Tuning of
+// Simple Regulators with Specifications on Phase and Amplitude Margins, 1984.
+type AutoTuner struct {
+	// Config for the AutoTuner.
+	Config AutoTunerConfig
+	// State of the AutoTuner.
+	State AutoTunerState
+}
+
+// AutoTunerConfig contains configurable parameters for an AutoTuner.
+type AutoTunerConfig struct {
+	// SetPoint is the reference value the relay test oscillates the process variable around.
+	SetPoint float32
+	// RelayAmplitude is the magnitude of the relay output applied to the process.
+	RelayAmplitude float32
+	// Hysteresis is the dead-band around SetPoint used to suppress noise-triggered relay switches.
+	Hysteresis float32
+	// MinOscillations is the number of full oscillation periods required before the run can converge.
+	// At least three periods are always required, since convergence is judged on the last three.
+	MinOscillations int
+	// ConvergencePeriodTolerance is the maximum allowed ratio of the standard deviation to the mean
+	// of the last three observed periods for the run to be considered converged.
+	ConvergencePeriodTolerance float32
+	// Timeout is the maximum elapsed time before the run gives up without converging.
+	Timeout time.Duration
+	// Rule selects which formula is used to derive gains from the identified Ku and Tu.
+	Rule TuningRule
+}
+
+// AutoTunerState holds mutable state for an AutoTuner.
+type AutoTunerState struct {
+	// Elapsed is the total time elapsed since the run started.
+	Elapsed time.Duration
+	// RelayOutput is the relay output currently being applied to the process.
+	RelayOutput float32
+	// Initialized indicates whether the first Step call has set the initial relay phase.
+	Initialized bool
+	// Phase is the sign of the current relay output, +1 or -1.
+	Phase int
+	// PhaseMax is the running maximum of the process variable during the current relay phase.
+	PhaseMax float32
+	// PhaseMin is the running minimum of the process variable during the current relay phase.
+	PhaseMin float32
+	// HavePeak indicates whether a peak has been recorded yet.
+	HavePeak bool
+	// HaveTrough indicates whether a trough has been recorded yet.
+	HaveTrough bool
+	// LastPeakTime is the Elapsed value at the most recently recorded peak.
+	LastPeakTime time.Duration
+	// LastPeakValue is the process variable value at the most recently recorded peak.
+	LastPeakValue float32
+	// LastTroughValue is the process variable value at the most recently recorded trough.
+	LastTroughValue float32
+	// Periods are the observed peak-to-peak time intervals, one per completed oscillation.
+	Periods []time.Duration
+	// Amplitudes are the observed peak-to-trough half-amplitudes, one per completed oscillation.
+	Amplitudes []float32
+	// Done indicates the run has finished, either by converging or by timing out.
+	Done bool
+	// Result is the outcome of the run. It is the zero value until Done is true, and has
+	// UltimatePeriod zero if the run timed out before a single oscillation completed.
+	Result AutoTunerResult
+}
+
+// AutoTunerResult holds the outcome of a completed relay-feedback identification run.
+type AutoTunerResult struct {
+	// Converged indicates whether the run reached a stable period estimate before Timeout.
+	Converged bool
+	// UltimateGain (Ku) is the describing-function estimate of the process gain at the
+	// point of sustained oscillation: Ku = (4 * RelayAmplitude) / (π * oscillation amplitude).
+	UltimateGain float32
+	// UltimatePeriod (Tu) is the mean peak-to-peak period of the sustained oscillation.
+	UltimatePeriod time.Duration
+	// ProportionalGain is the tuned P gain.
+	ProportionalGain float32
+	// IntegralGain is the tuned I gain.
+	IntegralGain float32
+	// DerivativeGain is the tuned D gain.
+	DerivativeGain float32
+}
+
+// ApplyTo copies the tuned gains into a ControllerConfig, leaving its other fields untouched.
+func (r AutoTunerResult) ApplyTo(c *ControllerConfig) {
+	c.ProportionalGain = r.ProportionalGain
+	c.IntegralGain = r.IntegralGain
+	c.DerivativeGain = r.DerivativeGain
+}
+
+// ApplyToTrackingController copies the tuned gains into a TrackingControllerConfig,
+// leaving its other fields untouched.
+func (r AutoTunerResult) ApplyToTrackingController(c *TrackingControllerConfig) {
+	c.ProportionalGain = r.ProportionalGain
+	c.IntegralGain = r.IntegralGain
+	c.DerivativeGain = r.DerivativeGain
+}
+
+// ApplyToAntiWindupController copies the tuned gains into an AntiWindupControllerConfig,
+// leaving its other fields untouched.
+func (r AutoTunerResult) ApplyToAntiWindupController(c *AntiWindupControllerConfig) {
+	c.ProportionalGain = r.ProportionalGain
+	c.IntegralGain = r.IntegralGain
+	c.DerivativeGain = r.DerivativeGain
+}
+
+// ApplyToPIController copies the tuned P and I gains into a PIControllerConfig, leaving its
+// other fields untouched. The tuned D gain is discarded, since PIControllerConfig has no
+// derivative term.
+func (r AutoTunerResult) ApplyToPIController(c *PIControllerConfig) {
+	c.ProportionalGain = r.ProportionalGain
+	c.IntegralGain = r.IntegralGain
+}
+
+// Reset the auto-tuner state.
+func (t *AutoTuner) Reset() {
+	t.State = AutoTunerState{}
+}
+
+// Step advances the relay-feedback identification run by one sampling interval.
+// actual is the current value of the process variable. It returns the relay
+// output to apply over dt and whether the run has finished. Once done is true,
+// Step keeps returning the last relay output and the result is available in
+// t.State.Result.
+func (t *AutoTuner) Step(actual float32, dt time.Duration) (output float32, done bool) {
+	if t.State.Done {
+		return t.State.RelayOutput, true
+	}
+	t.State.Elapsed += dt
+	e := t.Config.SetPoint - actual
+
+	if !t.State.Initialized {
+		t.State.Initialized = true
+		t.State.Phase = 1
+		if e < 0 {
+			t.State.Phase = -1
+		}
+		t.State.RelayOutput = float32(t.State.Phase) * t.Config.RelayAmplitude
+		t.State.PhaseMax = actual
+		t.State.PhaseMin = actual
+		return t.State.RelayOutput, false
+	}
+
+	t.State.PhaseMax = max(t.State.PhaseMax, actual)
+	t.State.PhaseMin = min(t.State.PhaseMin, actual)
+
+	newPhase := t.State.Phase
+	switch {
+	case e > t.Config.Hysteresis:
+		newPhase = 1
+	case e < -t.Config.Hysteresis:
+		newPhase = -1
+	}
+
+	if newPhase != t.State.Phase {
+		if t.State.Phase > 0 {
+			t.recordPeak(t.State.PhaseMax)
+		} else {
+			t.recordTrough(t.State.PhaseMin)
+		}
+		t.State.Phase = newPhase
+		t.State.RelayOutput = float32(newPhase) * t.Config.RelayAmplitude
+		t.State.PhaseMax = actual
+		t.State.PhaseMin = actual
+	}
+
+	if t.converged() || (t.Config.Timeout > 0 && t.State.Elapsed >= t.Config.Timeout) {
+		t.finish()
+	}
+
+	return t.State.RelayOutput, t.State.Done
+}
+
+// recordPeak records a local maximum of the process variable and, if a prior peak and
+// trough are available, derives a period and amplitude sample from it.
+func (t *AutoTuner) recordPeak(value float32) {
+	if t.State.HavePeak {
+		t.State.Periods = append(t.State.Periods, t.State.Elapsed-t.State.LastPeakTime)
+	}
+	if t.State.HaveTrough {
+		t.State.Amplitudes = append(t.State.Amplitudes, (value-t.State.LastTroughValue)/2)
+	}
+	t.State.LastPeakTime = t.State.Elapsed
+	t.State.LastPeakValue = value
+	t.State.HavePeak = true
+}
+
+// recordTrough records a local minimum of the process variable.
+func (t *AutoTuner) recordTrough(value float32) {
+	t.State.LastTroughValue = value
+	t.State.HaveTrough = true
+}
+
+// converged reports whether the last three observed periods are stable enough to
+// produce a reliable Tu estimate.
+func (t *AutoTuner) converged() bool {
+	n := len(t.State.Periods)
+	if n < t.Config.MinOscillations || n < 3 {
+		return false
+	}
+	last3 := t.State.Periods[n-3:]
+	mean := (seconds(last3[0]) + seconds(last3[1]) + seconds(last3[2])) / 3
+	if mean <= 0 {
+		return false
+	}
+	var variance float32
+	for _, p := range last3 {
+		d := seconds(p) - mean
+		variance += d * d
+	}
+	variance /= 3
+	stddev := float32(math.Sqrt(float64(variance)))
+	return stddev/mean <= t.Config.ConvergencePeriodTolerance
+}
+
+// finish marks the run done and computes the result from the periods and amplitudes
+// observed so far.
+func (t *AutoTuner) finish() {
+	t.State.Done = true
+	n := len(t.State.Periods)
+	if n == 0 || len(t.State.Amplitudes) == 0 {
+		return
+	}
+
+	var periodSum time.Duration
+	for _, p := range t.State.Periods {
+		periodSum += p
+	}
+	tu := periodSum / time.Duration(n)
+
+	var ampSum float32
+	for _, a := range t.State.Amplitudes {
+		ampSum += Abs(a)
+	}
+	amplitude := ampSum / float32(len(t.State.Amplitudes))
+	if amplitude <= 0 {
+		return
+	}
+
+	ku := (4 * t.Config.RelayAmplitude) / (math.Pi * amplitude)
+
+	result := AutoTunerResult{
+		Converged:      t.converged(),
+		UltimateGain:   ku,
+		UltimatePeriod: tu,
+	}
+
+	tuSeconds := seconds(tu)
+	var kp, ti, td float32
+	switch t.Config.Rule {
+	case PessenIntegral:
+		kp, ti, td = 0.7*ku, 0.4*tuSeconds, 0.15*tuSeconds
+	case SomeOvershoot:
+		kp, ti, td = 0.33*ku, 0.5*tuSeconds, 0.33*tuSeconds
+	case NoOvershoot:
+		kp, ti, td = 0.2*ku, 0.5*tuSeconds, 0.33*tuSeconds
+	default:
+		kp, ti, td = 0.6*ku, 0.5*tuSeconds, 0.125*tuSeconds
+	}
+	result.ProportionalGain = kp
+	if ti > 0 {
+		result.IntegralGain = kp / ti
+	}
+	result.DerivativeGain = kp * td
+
+	t.State.Result = result
+}
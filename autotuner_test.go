@@ -0,0 +1,183 @@
+package tinypid
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// driveSineOscillation feeds a noiseless sine-wave process variable of the given period and
+// amplitude through t.Step for the given number of periods, simulating a process that is
+// already oscillating at its ultimate period under relay feedback. Hysteresis must be set close
+// to amplitude so that the relay only switches near the true peaks and troughs of the wave,
+// letting the identified amplitude closely track the real one.
+func driveSineOscillation(t *AutoTuner, period time.Duration, amplitude float32, periods int, dt time.Duration) {
+	steps := int(period/dt) * periods
+	omega := 2 * math.Pi / seconds(period)
+	for i := 0; i <= steps; i++ {
+		elapsed := time.Duration(i) * dt
+		actual := amplitude * float32(math.Sin(float64(omega)*float64(seconds(elapsed))))
+		t.Step(actual, dt)
+		if t.State.Done {
+			return
+		}
+	}
+}
+
+func withinTolerance(got, want, tolerance float32) bool {
+	diff := got - want
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= tolerance*want
+}
+
+func TestAutoTunerConvergesToKnownUltimateGainAndPeriod(t *testing.T) {
+	const (
+		period    = 2 * time.Second
+		amplitude = float32(5.0)
+		hyst      = float32(4.95)
+		dt        = 5 * time.Millisecond
+	)
+
+	tuner := AutoTuner{
+		Config: AutoTunerConfig{
+			RelayAmplitude:             2.0,
+			Hysteresis:                 hyst,
+			MinOscillations:            3,
+			ConvergencePeriodTolerance: 0.01,
+			Timeout:                    10 * time.Second,
+			Rule:                       ZieglerNichols,
+		},
+	}
+	driveSineOscillation(&tuner, period, amplitude, 6, dt)
+
+	if !tuner.State.Done {
+		t.Fatalf("expected run to finish within the simulated window")
+	}
+	if !tuner.State.Result.Converged {
+		t.Fatalf("expected run to converge, got %+v", tuner.State.Result)
+	}
+
+	wantKu := 4 * tuner.Config.RelayAmplitude / (math.Pi * amplitude)
+	if !withinTolerance(tuner.State.Result.UltimateGain, wantKu, 0.05) {
+		t.Fatalf("UltimateGain = %v, want ~%v", tuner.State.Result.UltimateGain, wantKu)
+	}
+	if !withinTolerance(seconds(tuner.State.Result.UltimatePeriod), seconds(period), 0.05) {
+		t.Fatalf("UltimatePeriod = %v, want ~%v", tuner.State.Result.UltimatePeriod, period)
+	}
+
+	wantKp := 0.6 * wantKu
+	wantTi := 0.5 * seconds(period)
+	wantTd := 0.125 * seconds(period)
+	wantKi := wantKp / wantTi
+	wantKd := wantKp * wantTd
+	if !withinTolerance(tuner.State.Result.ProportionalGain, wantKp, 0.05) {
+		t.Fatalf("ProportionalGain = %v, want ~%v", tuner.State.Result.ProportionalGain, wantKp)
+	}
+	if !withinTolerance(tuner.State.Result.IntegralGain, wantKi, 0.05) {
+		t.Fatalf("IntegralGain = %v, want ~%v", tuner.State.Result.IntegralGain, wantKi)
+	}
+	if !withinTolerance(tuner.State.Result.DerivativeGain, wantKd, 0.05) {
+		t.Fatalf("DerivativeGain = %v, want ~%v", tuner.State.Result.DerivativeGain, wantKd)
+	}
+}
+
+func TestAutoTunerTuningRules(t *testing.T) {
+	cases := []struct {
+		rule                         TuningRule
+		kpFactor, tiFactor, tdFactor float32
+	}{
+		{ZieglerNichols, 0.6, 0.5, 0.125},
+		{PessenIntegral, 0.7, 0.4, 0.15},
+		{SomeOvershoot, 0.33, 0.5, 0.33},
+		{NoOvershoot, 0.2, 0.5, 0.33},
+	}
+	for _, tc := range cases {
+		tuner := AutoTuner{
+			Config: AutoTunerConfig{
+				RelayAmplitude:             2.0,
+				Hysteresis:                 4.95,
+				MinOscillations:            3,
+				ConvergencePeriodTolerance: 0.01,
+				Timeout:                    10 * time.Second,
+				Rule:                       tc.rule,
+			},
+		}
+		driveSineOscillation(&tuner, 2*time.Second, 5.0, 6, 5*time.Millisecond)
+
+		if !tuner.State.Done || !tuner.State.Result.Converged {
+			t.Fatalf("rule %v: expected convergence, got %+v", tc.rule, tuner.State.Result)
+		}
+
+		ku := tuner.State.Result.UltimateGain
+		tuSeconds := seconds(tuner.State.Result.UltimatePeriod)
+		wantKp := tc.kpFactor * ku
+		wantKi := wantKp / (tc.tiFactor * tuSeconds)
+		wantKd := wantKp * tc.tdFactor * tuSeconds
+		if !withinTolerance(tuner.State.Result.ProportionalGain, wantKp, 0.01) {
+			t.Errorf("rule %v: ProportionalGain = %v, want ~%v", tc.rule, tuner.State.Result.ProportionalGain, wantKp)
+		}
+		if !withinTolerance(tuner.State.Result.IntegralGain, wantKi, 0.01) {
+			t.Errorf("rule %v: IntegralGain = %v, want ~%v", tc.rule, tuner.State.Result.IntegralGain, wantKi)
+		}
+		if !withinTolerance(tuner.State.Result.DerivativeGain, wantKd, 0.01) {
+			t.Errorf("rule %v: DerivativeGain = %v, want ~%v", tc.rule, tuner.State.Result.DerivativeGain, wantKd)
+		}
+	}
+}
+
+func TestAutoTunerTimesOutWithoutOscillation(t *testing.T) {
+	tuner := AutoTuner{
+		Config: AutoTunerConfig{
+			SetPoint:                   10,
+			RelayAmplitude:             2.0,
+			Hysteresis:                 0.01,
+			MinOscillations:            4,
+			ConvergencePeriodTolerance: 0.01,
+			Timeout:                    1 * time.Second,
+			Rule:                       ZieglerNichols,
+		},
+	}
+	dt := 10 * time.Millisecond
+	for i := 0; i < 200; i++ {
+		// A process variable that never moves never crosses the hysteresis band, so the relay
+		// never switches and no oscillation period is ever observed.
+		_, done := tuner.Step(0, dt)
+		if done {
+			break
+		}
+	}
+
+	if !tuner.State.Done {
+		t.Fatalf("expected run to time out")
+	}
+	if tuner.State.Result.Converged {
+		t.Fatalf("expected run not to converge, got %+v", tuner.State.Result)
+	}
+	if tuner.State.Result.UltimatePeriod != 0 || tuner.State.Result.ProportionalGain != 0 {
+		t.Fatalf("expected zero-value result when no oscillation was observed, got %+v", tuner.State.Result)
+	}
+}
+
+func TestAutoTunerFinishGuardsZeroAmplitude(t *testing.T) {
+	tuner := AutoTuner{
+		Config: AutoTunerConfig{
+			RelayAmplitude: 2.0,
+			Rule:           ZieglerNichols,
+		},
+		State: AutoTunerState{
+			Periods:    []time.Duration{time.Second, time.Second, time.Second},
+			Amplitudes: []float32{0, 0, 0},
+		},
+	}
+
+	tuner.finish()
+
+	if !tuner.State.Done {
+		t.Fatalf("expected finish to mark the run done")
+	}
+	if (tuner.State.Result != AutoTunerResult{}) {
+		t.Fatalf("expected zero-value result when observed amplitude is zero, got %+v", tuner.State.Result)
+	}
+}
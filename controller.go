@@ -57,3 +57,16 @@ func (c *Controller) Update(input ControllerInput) {
 func (c *Controller) Reset() {
 	c.State = ControllerState{}
 }
+
+// SetConfig applies newConfig as a bumpless update: when the new IntegralGain is nonzero,
+// ControlErrorIntegral is re-seeded so the next Update's ControlSignal is continuous with the
+// last one despite the P and D term jumps caused by the new gains. When the new IntegralGain is
+// zero, ControlErrorIntegral is left as-is, since it no longer contributes to ControlSignal.
+func (c *Controller) SetConfig(newConfig ControllerConfig) {
+	c.Config = newConfig
+	if newConfig.IntegralGain != 0 {
+		c.State.ControlErrorIntegral = (c.State.ControlSignal -
+			newConfig.ProportionalGain*c.State.ControlError -
+			newConfig.DerivativeGain*c.State.ControlErrorDerivative) / newConfig.IntegralGain
+	}
+}
@@ -0,0 +1,23 @@
+package tinypid
+
+// DerivativeSource selects which signal the derivative term of a setpoint-weighted
+// controller acts on.
+type DerivativeSource int
+
+const (
+	// DerivativeOnError computes the derivative from the (optionally setpoint-weighted)
+	// control error. This is the default.
+	DerivativeOnError DerivativeSource = iota
+	// DerivativeOnMeasurement computes the derivative from the negated measurement alone,
+	// eliminating derivative kick on step setpoint changes.
+	DerivativeOnMeasurement
+)
+
+// setpointWeight returns w, treating the zero value as 1 so that a config left at its Go
+// zero value reproduces full setpoint weighting (no 2-DoF behavior change).
+func setpointWeight(w float32) float32 {
+	if w == 0 {
+		return 1
+	}
+	return w
+}
@@ -0,0 +1,90 @@
+package tinypid
+
+import "time"
+
+// IncrementalController implements a velocity-form (incremental) PID controller: instead of a
+// control signal, Update returns the change in control signal since the previous call,
+//
+//	Δu[k] = Kp*(e[k]-e[k-1]) + Ki*e[k]*dt + Kd*(e[k]-2e[k-1]+e[k-2])/dt
+//
+// Because the integral action is implicit in the increment, windup is avoided naturally once
+// the accumulated ControlSignal is clamped to [MinOutput, MaxOutput], which makes this form
+// attractive for actuators that accept incremental commands, such as stepper drivers and valves.
+type IncrementalController struct {
+	// Config for the IncrementalController.
+	Config IncrementalControllerConfig
+	// State of the IncrementalController.
+	State IncrementalControllerState
+}
+
+// IncrementalControllerConfig contains configurable parameters for an IncrementalController.
+type IncrementalControllerConfig struct {
+	// ProportionalGain is the P part gain.
+	ProportionalGain float32
+	// IntegralGain is the I part gain.
+	IntegralGain float32
+	// DerivativeGain is the D part gain.
+	DerivativeGain float32
+	// LowPassTimeConstant is the time constant of an EMA filter applied to the double difference
+	// that drives the D term. Zero disables filtering.
+	LowPassTimeConstant time.Duration
+	// MaxOutput is the max output from the PID.
+	MaxOutput float32
+	// MinOutput is the min output from the PID.
+	MinOutput float32
+}
+
+// IncrementalControllerState holds mutable state for an IncrementalController.
+type IncrementalControllerState struct {
+	// ErrorPrevious is e[k-1], the control error from the previous Update call.
+	ErrorPrevious float32
+	// ErrorPreviousPrevious is e[k-2], the control error from two Update calls ago.
+	ErrorPreviousPrevious float32
+	// ControlErrorDoubleDifference is the (optionally EMA-filtered) double difference of the
+	// control error that drives the D term.
+	ControlErrorDoubleDifference float32
+	// ControlSignal is the accumulated control signal output of the controller, clamped to
+	// [MinOutput, MaxOutput].
+	ControlSignal float32
+}
+
+// IncrementalControllerInput holds the input parameters to an IncrementalController.
+type IncrementalControllerInput struct {
+	// ReferenceSignal is the reference value for the signal to control.
+	ReferenceSignal float32
+	// ActualSignal is the actual value of the signal to control.
+	ActualSignal float32
+	// SamplingInterval is the time interval elapsed since the previous call of the controller Update method.
+	SamplingInterval time.Duration
+}
+
+// Reset the controller state.
+func (c *IncrementalController) Reset() {
+	c.State = IncrementalControllerState{}
+}
+
+// Update the controller state and return delta, the change in ControlSignal since the
+// previous call.
+func (c *IncrementalController) Update(input IncrementalControllerInput) (delta float32) {
+	e := input.ReferenceSignal - input.ActualSignal
+	dt := seconds(input.SamplingInterval)
+
+	doubleDifference := e - 2*c.State.ErrorPrevious + c.State.ErrorPreviousPrevious
+	if c.Config.LowPassTimeConstant > 0 {
+		alpha := dt / (seconds(c.Config.LowPassTimeConstant) + dt)
+		doubleDifference = c.State.ControlErrorDoubleDifference + alpha*(doubleDifference-c.State.ControlErrorDoubleDifference)
+	}
+
+	rawDelta := c.Config.ProportionalGain*(e-c.State.ErrorPrevious) + c.Config.IntegralGain*e*dt +
+		c.Config.DerivativeGain*doubleDifference/dt
+
+	previousControlSignal := c.State.ControlSignal
+	c.State.ControlSignal = max(c.Config.MinOutput, min(c.Config.MaxOutput, previousControlSignal+rawDelta))
+	delta = c.State.ControlSignal - previousControlSignal
+
+	c.State.ControlErrorDoubleDifference = doubleDifference
+	c.State.ErrorPreviousPrevious = c.State.ErrorPrevious
+	c.State.ErrorPrevious = e
+
+	return delta
+}
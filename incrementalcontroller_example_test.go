@@ -0,0 +1,76 @@
+package tinypid_test
+
+import (
+	"fmt"
+	"time"
+
+	pid "github.com/mikesmitty/tinypid"
+)
+
+// ExampleIncrementalController shows that, for a small setpoint step, accumulating the deltas
+// from an IncrementalController tracks the output of an equivalent position-form Controller,
+// and that a sustained, larger error saturates the accumulator gracefully instead of winding up.
+func ExampleIncrementalController() {
+	gains := struct {
+		Kp, Ki, Kd float32
+	}{Kp: 2.0, Ki: 1.0, Kd: 0.5}
+
+	incremental := pid.IncrementalController{
+		Config: pid.IncrementalControllerConfig{
+			ProportionalGain: gains.Kp,
+			IntegralGain:     gains.Ki,
+			DerivativeGain:   gains.Kd,
+			MaxOutput:        1000,
+			MinOutput:        -1000,
+		},
+	}
+	position := pid.Controller{
+		Config: pid.ControllerConfig{
+			ProportionalGain: gains.Kp,
+			IntegralGain:     gains.Ki,
+			DerivativeGain:   gains.Kd,
+		},
+	}
+
+	dt := 100 * time.Millisecond
+	actual := float32(0)
+	for i := 0; i < 5; i++ {
+		incremental.Update(pid.IncrementalControllerInput{
+			ReferenceSignal:  1,
+			ActualSignal:     actual,
+			SamplingInterval: dt,
+		})
+		position.Update(pid.ControllerInput{
+			ReferenceSignal:  1,
+			ActualSignal:     actual,
+			SamplingInterval: dt,
+		})
+		actual += 0.1
+	}
+	fmt.Printf("incremental=%.4f position=%.4f\n", incremental.State.ControlSignal, position.State.ControlSignal)
+
+	// A sustained, saturating error drives the accumulator to MaxOutput and the delta to zero,
+	// instead of winding up the way a naive position-form integral would.
+	saturating := pid.IncrementalController{
+		Config: pid.IncrementalControllerConfig{
+			ProportionalGain: gains.Kp,
+			IntegralGain:     gains.Ki,
+			DerivativeGain:   gains.Kd,
+			MaxOutput:        10,
+			MinOutput:        -10,
+		},
+	}
+	var lastDelta float32
+	for i := 0; i < 50; i++ {
+		lastDelta = saturating.Update(pid.IncrementalControllerInput{
+			ReferenceSignal:  100,
+			ActualSignal:     0,
+			SamplingInterval: dt,
+		})
+	}
+	fmt.Printf("saturated=%.4f lastDelta=%.4f\n", saturating.State.ControlSignal, lastDelta)
+
+	// Output:
+	// incremental=1.1000 position=1.1000
+	// saturated=10.0000 lastDelta=0.0000
+}
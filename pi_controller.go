@@ -57,3 +57,16 @@ func (c *PIController) Update(input PIControllerInput) {
 func (c *PIController) Reset() {
 	c.State = PIControllerState{}
 }
+
+// SetConfig applies newConfig as a bumpless update: if IntegralGain changes,
+// ControlErrorIntegral is rescaled so that the integral term's contribution to ControlSignal
+// is unchanged. PIControllerState does not retain the last control error, so unlike the other
+// controllers SetConfig cannot also re-seed the integrator to cancel the P term's jump.
+func (c *PIController) SetConfig(newConfig PIControllerConfig) {
+	oldConfig := c.Config
+	if oldConfig.IntegralGain != 0 && newConfig.IntegralGain != 0 {
+		c.State.ControlErrorIntegral *= oldConfig.IntegralGain / newConfig.IntegralGain
+	}
+	c.Config = newConfig
+	c.State.ControlErrorIntegral = max(c.Config.MinIntegralError, min(c.Config.MaxIntegralError, c.State.ControlErrorIntegral))
+}
@@ -0,0 +1,178 @@
+package tinypid_test
+
+import (
+	"testing"
+	"time"
+
+	pid "github.com/mikesmitty/tinypid"
+)
+
+func TestAntiWindupControllerSetConfigBumpless(t *testing.T) {
+	c := pid.AntiWindupController{
+		Config: pid.AntiWindupControllerConfig{
+			ProportionalGain:    1.0,
+			IntegralGain:        1.0,
+			DerivativeGain:      0.0,
+			LowPassTimeConstant: time.Second,
+			MaxOutput:           1000,
+			MinOutput:           -1000,
+		},
+	}
+	c.Update(pid.AntiWindupControllerInput{
+		ReferenceSignal:  10,
+		ActualSignal:     0,
+		SamplingInterval: 100 * time.Millisecond,
+	})
+	before := c.State.ControlSignal
+
+	c.SetConfig(pid.AntiWindupControllerConfig{
+		ProportionalGain:    3.0,
+		IntegralGain:        2.0,
+		DerivativeGain:      0.0,
+		LowPassTimeConstant: time.Second,
+		MaxOutput:           1000,
+		MinOutput:           -1000,
+	})
+
+	got := c.Config.ProportionalGain*c.State.ControlError + c.Config.IntegralGain*c.State.ControlErrorIntegral +
+		c.Config.DerivativeGain*c.State.ControlErrorDerivative
+	if diff := got - before; diff > 1e-3 || diff < -1e-3 {
+		t.Fatalf("SetConfig did not re-seed bumplessly: before=%v after=%v", before, got)
+	}
+}
+
+func TestAntiWindupControllerMaxOutputSlewRate(t *testing.T) {
+	c := pid.AntiWindupController{
+		Config: pid.AntiWindupControllerConfig{
+			ProportionalGain:    100.0,
+			LowPassTimeConstant: time.Second,
+			MaxOutput:           1000,
+			MinOutput:           -1000,
+			MaxOutputSlewRate:   1.0,
+		},
+	}
+	c.Update(pid.AntiWindupControllerInput{
+		ReferenceSignal:  10,
+		ActualSignal:     0,
+		SamplingInterval: 1 * time.Second,
+	})
+	if c.State.ControlSignal != 1.0 {
+		t.Fatalf("expected slew-limited output of 1.0, got %v", c.State.ControlSignal)
+	}
+}
+
+func TestControllerSetConfigBumpless(t *testing.T) {
+	c := pid.Controller{
+		Config: pid.ControllerConfig{
+			ProportionalGain: 1.0,
+			IntegralGain:     1.0,
+			DerivativeGain:   0.0,
+		},
+	}
+	c.Update(pid.ControllerInput{
+		ReferenceSignal:  10,
+		ActualSignal:     0,
+		SamplingInterval: 100 * time.Millisecond,
+	})
+	before := c.State.ControlSignal
+
+	c.SetConfig(pid.ControllerConfig{
+		ProportionalGain: 3.0,
+		IntegralGain:     2.0,
+		DerivativeGain:   0.0,
+	})
+
+	got := c.Config.ProportionalGain*c.State.ControlError + c.Config.IntegralGain*c.State.ControlErrorIntegral +
+		c.Config.DerivativeGain*c.State.ControlErrorDerivative
+	if diff := got - before; diff > 1e-3 || diff < -1e-3 {
+		t.Fatalf("SetConfig did not re-seed bumplessly: before=%v after=%v", before, got)
+	}
+}
+
+func TestPIControllerSetConfigBumpless(t *testing.T) {
+	c := pid.PIController{
+		Config: pid.PIControllerConfig{
+			ProportionalGain: 1.0,
+			IntegralGain:     1.0,
+			MaxIntegralError: 1000,
+			MinIntegralError: -1000,
+			MaxOutput:        1000,
+			MinOutput:        -1000,
+		},
+	}
+	c.Update(pid.PIControllerInput{
+		ReferenceSignal:  10,
+		ActualSignal:     0,
+		SamplingInterval: 100 * time.Millisecond,
+	})
+	beforeIntegralTerm := c.Config.IntegralGain * c.State.ControlErrorIntegral
+
+	c.SetConfig(pid.PIControllerConfig{
+		ProportionalGain: 3.0,
+		IntegralGain:     2.0,
+		MaxIntegralError: 1000,
+		MinIntegralError: -1000,
+		MaxOutput:        1000,
+		MinOutput:        -1000,
+	})
+
+	afterIntegralTerm := c.Config.IntegralGain * c.State.ControlErrorIntegral
+	if diff := afterIntegralTerm - beforeIntegralTerm; diff > 1e-3 || diff < -1e-3 {
+		t.Fatalf("SetConfig did not rescale the integral term bumplessly: before=%v after=%v", beforeIntegralTerm, afterIntegralTerm)
+	}
+}
+
+func TestTrackingControllerSetConfigBumpless(t *testing.T) {
+	c := pid.TrackingController{
+		Config: pid.TrackingControllerConfig{
+			ProportionalGain:    1.0,
+			IntegralGain:        1.0,
+			DerivativeGain:      0.0,
+			LowPassTimeConstant: time.Second,
+			MaxOutput:           1000,
+			MinOutput:           -1000,
+		},
+	}
+	c.Update(pid.TrackingControllerInput{
+		ReferenceSignal:      10,
+		ActualSignal:         0,
+		AppliedControlSignal: 0,
+		SamplingInterval:     100 * time.Millisecond,
+	})
+	before := c.State.ControlSignal
+
+	c.SetConfig(pid.TrackingControllerConfig{
+		ProportionalGain:    3.0,
+		IntegralGain:        2.0,
+		DerivativeGain:      0.0,
+		LowPassTimeConstant: time.Second,
+		MaxOutput:           1000,
+		MinOutput:           -1000,
+	})
+
+	got := c.Config.ProportionalGain*c.State.ControlError + c.Config.IntegralGain*c.State.ControlErrorIntegral +
+		c.Config.DerivativeGain*c.State.ControlErrorDerivative
+	if diff := got - before; diff > 1e-3 || diff < -1e-3 {
+		t.Fatalf("SetConfig did not re-seed bumplessly: before=%v after=%v", before, got)
+	}
+}
+
+func TestTrackingControllerMaxOutputSlewRate(t *testing.T) {
+	c := pid.TrackingController{
+		Config: pid.TrackingControllerConfig{
+			ProportionalGain:    100.0,
+			LowPassTimeConstant: time.Second,
+			MaxOutput:           1000,
+			MinOutput:           -1000,
+			MaxOutputSlewRate:   1.0,
+		},
+	}
+	c.Update(pid.TrackingControllerInput{
+		ReferenceSignal:  10,
+		ActualSignal:     0,
+		SamplingInterval: 1 * time.Second,
+	})
+	if c.State.ControlSignal != 1.0 {
+		t.Fatalf("expected slew-limited output of 1.0, got %v", c.State.ControlSignal)
+	}
+}
@@ -0,0 +1,66 @@
+package tinypid_test
+
+import (
+	"testing"
+	"time"
+
+	pid "github.com/mikesmitty/tinypid"
+)
+
+func TestAntiWindupControllerSetpointWeightDefaultsMatchUnweighted(t *testing.T) {
+	config := pid.AntiWindupControllerConfig{
+		ProportionalGain:    2.0,
+		IntegralGain:        1.0,
+		DerivativeGain:      1.0,
+		LowPassTimeConstant: time.Second,
+		MaxOutput:           1000,
+		MinOutput:           -1000,
+	}
+	input := pid.AntiWindupControllerInput{
+		ReferenceSignal:  10,
+		ActualSignal:     0,
+		SamplingInterval: 100 * time.Millisecond,
+	}
+
+	unweighted := pid.AntiWindupController{Config: config}
+	unweighted.Update(input)
+
+	weighted := pid.AntiWindupController{Config: config}
+	weighted.Config.ProportionalSetpointWeight = 1.0
+	weighted.Config.DerivativeSetpointWeight = 1.0
+	weighted.Update(input)
+
+	if unweighted.State.ControlSignal != weighted.State.ControlSignal {
+		t.Fatalf("zero-value weights should match explicit b=1, c=1: got %v vs %v",
+			unweighted.State.ControlSignal, weighted.State.ControlSignal)
+	}
+}
+
+func TestTrackingControllerSetpointWeightDefaultsMatchUnweighted(t *testing.T) {
+	config := pid.TrackingControllerConfig{
+		ProportionalGain:    2.0,
+		IntegralGain:        1.0,
+		DerivativeGain:      1.0,
+		LowPassTimeConstant: time.Second,
+		MaxOutput:           1000,
+		MinOutput:           -1000,
+	}
+	input := pid.TrackingControllerInput{
+		ReferenceSignal:  10,
+		ActualSignal:     0,
+		SamplingInterval: 100 * time.Millisecond,
+	}
+
+	unweighted := pid.TrackingController{Config: config}
+	unweighted.Update(input)
+
+	weighted := pid.TrackingController{Config: config}
+	weighted.Config.ProportionalSetpointWeight = 1.0
+	weighted.Config.DerivativeSetpointWeight = 1.0
+	weighted.Update(input)
+
+	if unweighted.State.ControlSignal != weighted.State.ControlSignal {
+		t.Fatalf("zero-value weights should match explicit b=1, c=1: got %v vs %v",
+			unweighted.State.ControlSignal, weighted.State.ControlSignal)
+	}
+}
@@ -0,0 +1,41 @@
+package tinypid_test
+
+import (
+	"testing"
+	"time"
+
+	pid "github.com/mikesmitty/tinypid"
+)
+
+func TestTrackingControllerConditionalIntegration(t *testing.T) {
+	c := pid.TrackingController{
+		Config: pid.TrackingControllerConfig{
+			ProportionalGain:    1.0,
+			IntegralGain:        1.0,
+			DerivativeGain:      0.0,
+			AntiWindUpGain:      0.0,
+			AntiWindupMode:      pid.ConditionalIntegration,
+			LowPassTimeConstant: time.Second,
+			MaxOutput:           1.0,
+			MinOutput:           -1.0,
+		},
+	}
+
+	// A sustained, saturating reference step should not wind the integrator up without bound,
+	// even with AntiWindUpGain left at zero.
+	for i := 0; i < 1000; i++ {
+		c.Update(pid.TrackingControllerInput{
+			ReferenceSignal:      100,
+			ActualSignal:         0,
+			AppliedControlSignal: c.State.ControlSignal,
+			SamplingInterval:     100 * time.Millisecond,
+		})
+	}
+
+	if c.State.ControlErrorIntegral > 200 {
+		t.Fatalf("ControlErrorIntegral grew unbounded: got %v", c.State.ControlErrorIntegral)
+	}
+	if c.State.ControlSignal != c.Config.MaxOutput {
+		t.Fatalf("expected saturated output %v, got %v", c.Config.MaxOutput, c.State.ControlSignal)
+	}
+}